@@ -0,0 +1,45 @@
+package rpc
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AddrLocker serializes operations that act on behalf of a given address, such as the
+// nonce-fetch-then-submit sequence in SendTransaction, SignTransaction and Resend. Without
+// it, concurrent calls for the same From address can race on the pending nonce and the node
+// will reject one of them with "nonce too low". Ported from go-ethereum's internal/ethapi.
+type AddrLocker struct {
+	mu    sync.Mutex
+	locks map[common.Address]*sync.Mutex
+}
+
+// NewAddrLocker returns an empty, ready-to-use AddrLocker.
+func NewAddrLocker() *AddrLocker {
+	return &AddrLocker{
+		locks: make(map[common.Address]*sync.Mutex),
+	}
+}
+
+// lock returns the per-address mutex, creating it if this is the first time address is seen.
+func (l *AddrLocker) lock(address common.Address) *sync.Mutex {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locks[address] == nil {
+		l.locks[address] = new(sync.Mutex)
+	}
+	return l.locks[address]
+}
+
+// LockAddr locks an account's mutex. This is used to prevent another tx getting the
+// same nonce until the lock is released. The mutex prevents the (an identical nonce) from
+// being read again during the time that the first transaction is being signed and broadcast.
+func (l *AddrLocker) LockAddr(address common.Address) {
+	l.lock(address).Lock()
+}
+
+// UnlockAddr unlocks the mutex of the given account.
+func (l *AddrLocker) UnlockAddr(address common.Address) {
+	l.lock(address).Unlock()
+}
@@ -0,0 +1,177 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	client "github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultBatchSize is the batch size used when a BatchConfig leaves BatchSize unset.
+const DefaultBatchSize = 100
+
+// BatchConfig configures how PublicTransactionPool groups calls into BatchCallContext
+// round trips and, optionally, retries individual items within a batch.
+type BatchConfig struct {
+	// BatchSize is the maximum number of calls sent in a single BatchCallContext round
+	// trip. Values <= 0 fall back to DefaultBatchSize.
+	BatchSize int
+	// Retry configures the optional per-item retry wrapper. A nil Retry disables retries.
+	Retry *RetryConfig
+}
+
+// DefaultBatchConfig returns the BatchConfig used by the plain constructors (NewEth,
+// NewPublicTransactionPool, ...): DefaultBatchSize and no retries.
+func DefaultBatchConfig() BatchConfig {
+	return BatchConfig{BatchSize: DefaultBatchSize}
+}
+
+// RetryConfig configures the exponential-backoff retry wrapper applied to individual
+// batch items so that indexer/backfill workloads can survive flaky upstream RPCs.
+type RetryConfig struct {
+	// MaxAttempts is the total number of times an item is tried, including the first.
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt; it doubles on each subsequent
+	// retry and is capped by MaxDelay. Each wait is jittered to a random duration between
+	// half and all of that computed backoff.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff. A zero value means uncapped.
+	MaxDelay time.Duration
+	// IsTransient decides whether a failed item should be retried. Defaults to
+	// IsTransientError when left nil.
+	IsTransient func(error) bool
+}
+
+// DefaultRetryConfig is a reasonable starting point for indexer/backfill workloads:
+// three attempts, 100ms base backoff capped at 2s, retrying on IsTransientError.
+func DefaultRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+		IsTransient: IsTransientError,
+	}
+}
+
+// IsTransientError is the default transient-error predicate used by RetryConfig: JSON-RPC
+// errors that carry a server-side error code (bad params, execution reverted, ...) are
+// treated as permanent, while anything else - timeouts, connection resets, rate limiting -
+// is assumed to be a transient upstream hiccup worth retrying.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var rpcErr client.Error
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+	return true
+}
+
+func (c *RetryConfig) isTransient(err error) bool {
+	if c.IsTransient != nil {
+		return c.IsTransient(err)
+	}
+	return IsTransientError(err)
+}
+
+func (c *RetryConfig) backoff(attempt int) time.Duration {
+	d := c.BaseDelay << uint(attempt)
+	if c.MaxDelay > 0 && d > c.MaxDelay {
+		d = c.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// batch groups client.BatchElem items into BatchCallContext round trips of at most
+// cfg.BatchSize, optionally retrying individually failed items per cfg.Retry.
+type batch struct {
+	client *client.Client
+	cfg    BatchConfig
+}
+
+func newBatch(c *client.Client, cfg BatchConfig) *batch {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	return &batch{client: c, cfg: cfg}
+}
+
+// run executes elems in chunks of b.cfg.BatchSize, leaving the outcome of each call in its
+// Result/Error fields.
+func (b *batch) run(ctx context.Context, elems []client.BatchElem) {
+	for start := 0; start < len(elems); start += b.cfg.BatchSize {
+		end := start + b.cfg.BatchSize
+		if end > len(elems) {
+			end = len(elems)
+		}
+		b.runChunk(ctx, elems[start:end])
+	}
+}
+
+// runChunk drives chunk through up to b.cfg.Retry.MaxAttempts rounds, always writing each
+// item's outcome back into chunk by index so elem.Error reflects its *last* attempt rather
+// than a stale failure from an earlier one that a retry went on to fix.
+func (b *batch) runChunk(ctx context.Context, chunk []client.BatchElem) {
+	pendingIdx := make([]int, len(chunk))
+	for i := range chunk {
+		pendingIdx[i] = i
+	}
+
+	attempts := 1
+	if b.cfg.Retry != nil {
+		attempts = b.cfg.Retry.MaxAttempts
+	}
+	if attempts < 1 {
+		// A misconfigured (zero or negative) MaxAttempts must not suppress the call
+		// entirely - always make at least one attempt.
+		attempts = 1
+	}
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		req := make([]client.BatchElem, len(pendingIdx))
+		for i, idx := range pendingIdx {
+			req[i] = chunk[idx]
+		}
+
+		err := b.client.BatchCallContext(ctx, req)
+		for i, idx := range pendingIdx {
+			chunk[idx].Error = req[i].Error
+			// A transport-level failure leaves per-item Error unset; surface it on
+			// every pending item so callers always get an explanation.
+			if err != nil && chunk[idx].Error == nil {
+				chunk[idx].Error = err
+			}
+		}
+
+		if b.cfg.Retry == nil || attempt == attempts-1 {
+			return
+		}
+
+		var nextIdx []int
+		for _, idx := range pendingIdx {
+			if chunk[idx].Error != nil && b.cfg.Retry.isTransient(chunk[idx].Error) {
+				nextIdx = append(nextIdx, idx)
+			}
+		}
+		if len(nextIdx) == 0 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.cfg.Retry.backoff(attempt)):
+		}
+
+		for _, idx := range nextIdx {
+			chunk[idx].Error = nil
+		}
+		pendingIdx = nextIdx
+	}
+}
@@ -0,0 +1,123 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	client "github.com/ethereum/go-ethereum/rpc"
+)
+
+// testEchoReq/testEchoResp model a minimal JSON-RPC 2.0 batch exchange for a single
+// "test_echo" method that returns its one string argument, letting a test server fail a
+// specific argument's first attempt and succeed on the retry.
+type testEchoReq struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params []string        `json:"params"`
+}
+
+type testEchoResp struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  *string         `json:"result,omitempty"`
+	Error   *testEchoRPCErr `json:"error,omitempty"`
+}
+
+type testEchoRPCErr struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// newFailOnceServer returns a JSON-RPC batch test server whose "test_echo" method echoes
+// its argument back, except that failArg returns a "retry-me" error on its first call.
+func newFailOnceServer(t *testing.T, failArg string) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	attempts := map[string]int{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []testEchoReq
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resps := make([]testEchoResp, len(reqs))
+		for i, req := range reqs {
+			arg := req.Params[0]
+
+			mu.Lock()
+			attempts[arg]++
+			n := attempts[arg]
+			mu.Unlock()
+
+			if arg == failArg && n == 1 {
+				resps[i] = testEchoResp{
+					Version: "2.0",
+					ID:      req.ID,
+					Error:   &testEchoRPCErr{Code: -32000, Message: "retry-me"},
+				}
+				continue
+			}
+			result := arg
+			resps[i] = testEchoResp{Version: "2.0", ID: req.ID, Result: &result}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resps); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+}
+
+// TestBatchRunChunkRetrySucceeds verifies that an item which transient-fails on its first
+// attempt and succeeds on retry comes back with a nil Error alongside its Result - not the
+// stale error from the failed attempt.
+func TestBatchRunChunkRetrySucceeds(t *testing.T) {
+	srv := newFailOnceServer(t, "retry-once")
+	defer srv.Close()
+
+	c, err := client.DialHTTP(srv.URL)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c.Close()
+
+	b := newBatch(c, BatchConfig{
+		BatchSize: 10,
+		Retry: &RetryConfig{
+			MaxAttempts: 2,
+			BaseDelay:   time.Millisecond,
+			IsTransient: func(err error) bool {
+				return err != nil && strings.Contains(err.Error(), "retry-me")
+			},
+		},
+	})
+
+	var resultA, resultB string
+	elems := []client.BatchElem{
+		{Method: "test_echo", Args: []interface{}{"retry-once"}, Result: &resultA},
+		{Method: "test_echo", Args: []interface{}{"ok"}, Result: &resultB},
+	}
+
+	b.run(context.Background(), elems)
+
+	if elems[0].Error != nil {
+		t.Errorf("item that succeeded on retry still has a stale error: %v", elems[0].Error)
+	}
+	if resultA != "retry-once" {
+		t.Errorf("result for retried item = %q, want %q", resultA, "retry-once")
+	}
+	if elems[1].Error != nil {
+		t.Errorf("unrelated item unexpectedly errored: %v", elems[1].Error)
+	}
+	if resultB != "ok" {
+		t.Errorf("result for unaffected item = %q, want %q", resultB, "ok")
+	}
+}
@@ -2,7 +2,10 @@ package rpc
 
 import (
 	"context"
+	"errors"
+	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -23,6 +26,38 @@ func NewEth(client *client.Client) Eth {
 	}
 }
 
+// NewEthWithSigner returns an Eth that signs transactions and eth_sign requests locally
+// with signer instead of asking the connected node to do so. This removes the need for
+// the node to manage or unlock the sending account.
+func NewEthWithSigner(client *client.Client, signer Signer) Eth {
+	return &eth{
+		PublicTransactionPool: NewPublicTransactionPoolWithSigner(client, signer),
+	}
+}
+
+// NewEthWithLocker returns an Eth whose PublicTransactionPool serializes nonce handling
+// through locker, allowing it to be shared with other PublicTransactionPool instances.
+func NewEthWithLocker(client *client.Client, locker *AddrLocker) Eth {
+	return &eth{
+		PublicTransactionPool: NewPublicTransactionPoolWithLocker(client, locker),
+	}
+}
+
+// NewEthWithSignerAndLocker combines NewEthWithSigner and NewEthWithLocker.
+func NewEthWithSignerAndLocker(client *client.Client, signer Signer, locker *AddrLocker) Eth {
+	return &eth{
+		PublicTransactionPool: NewPublicTransactionPoolWithSignerAndLocker(client, signer, locker),
+	}
+}
+
+// NewEthWithBatchConfig returns an Eth whose PublicTransactionPool batches and retries
+// GetTransactionsByHashes/GetTransactionReceipts calls according to cfg.
+func NewEthWithBatchConfig(client *client.Client, cfg BatchConfig) Eth {
+	return &eth{
+		PublicTransactionPool: NewPublicTransactionPoolWithBatchConfig(client, cfg),
+	}
+}
+
 // SendTxArgs represents the arguments to sumbit a new transaction into the transaction pool.
 type SendTxArgs struct {
 	From     common.Address `json:"from"`
@@ -31,7 +66,18 @@ type SendTxArgs struct {
 	GasPrice hexutil.Big    `json:"gasPrice"`
 	Value    hexutil.Big    `json:"value"`
 	Data     hexutil.Bytes  `json:"data"`
-	Nonce    hexutil.Uint64 `json:"nonce"`
+	// Nonce is a pointer so that an explicit nonce of 0 can be told apart from "not
+	// supplied" - buildTransaction only fetches the node's pending nonce when this is nil.
+	Nonce *hexutil.Uint64 `json:"nonce"`
+
+	// EIP-1559 fee market fields. MaxFeePerGas and MaxPriorityFeePerGas are only
+	// set for type-2 (dynamic-fee) transactions; GasPrice above remains the way
+	// to specify legacy and type-1 transactions.
+	MaxFeePerGas         *hexutil.Big      `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
+	AccessList           *types.AccessList `json:"accessList,omitempty"`
+	ChainID              *hexutil.Big      `json:"chainId,omitempty"`
+	Type                 *hexutil.Uint64   `json:"type,omitempty"`
 }
 
 // SignTransactionResult represents a RLP encoded signed transaction.
@@ -56,6 +102,42 @@ type RPCTransaction struct {
 	V                *hexutil.Big    `json:"v"`
 	R                *hexutil.Big    `json:"r"`
 	S                *hexutil.Big    `json:"s"`
+
+	// EIP-1559 / EIP-2930 fields, populated for type-1 and type-2 transactions.
+	MaxFeePerGas         *hexutil.Big      `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
+	AccessList           *types.AccessList `json:"accessList,omitempty"`
+	ChainID              *hexutil.Big      `json:"chainId,omitempty"`
+	Type                 hexutil.Uint64    `json:"type"`
+}
+
+// TransactionReceipt represents the receipt of a mined transaction.
+type TransactionReceipt struct {
+	TransactionHash   common.Hash     `json:"transactionHash"`
+	TransactionIndex  hexutil.Uint    `json:"transactionIndex"`
+	BlockHash         common.Hash     `json:"blockHash"`
+	BlockNumber       *hexutil.Big    `json:"blockNumber"`
+	From              common.Address  `json:"from"`
+	To                *common.Address `json:"to"`
+	CumulativeGasUsed hexutil.Uint64  `json:"cumulativeGasUsed"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice,omitempty"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	Logs              []*types.Log    `json:"logs"`
+	LogsBloom         types.Bloom     `json:"logsBloom"`
+	Type              hexutil.Uint64  `json:"type"`
+	// Status is non-nil for post-Byzantium receipts; Root is non-nil for pre-Byzantium
+	// receipts. Exactly one of the two is set.
+	Status *hexutil.Uint64 `json:"status,omitempty"`
+	Root   hexutil.Bytes   `json:"root,omitempty"`
+}
+
+// FeeHistoryResult is the response of a eth_feeHistory request.
+type FeeHistoryResult struct {
+	OldestBlock   *hexutil.Big     `json:"oldestBlock"`
+	BaseFeePerGas []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio  []float64        `json:"gasUsedRatio"`
+	Reward        [][]*hexutil.Big `json:"reward,omitempty"`
 }
 
 type PublicTransactionPool interface {
@@ -78,7 +160,27 @@ type PublicTransactionPool interface {
 	// GetRawTransactionByHash returns the bytes of the transaction for the given hash.
 	GetRawTransactionByHash(ctx context.Context, hash common.Hash) (hexutil.Bytes, error)
 	// GetTransactionReceipt returns the transaction receipt for the given transaction hash.
-	GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error)
+	GetTransactionReceipt(ctx context.Context, hash common.Hash) (*TransactionReceipt, error)
+	// GetTransactionReceiptsByBlock returns the receipts of every transaction in the given
+	// block in one round trip, using the eth_getBlockReceipts extension. This avoids having
+	// to fan out one GetTransactionReceipt call per hash returned by
+	// GetBlockTransactionCountByNumber/Hash.
+	GetTransactionReceiptsByBlock(ctx context.Context, blockNrOrHash client.BlockNumberOrHash) ([]*TransactionReceipt, error)
+	// GetTransactionsByHashes looks up multiple transactions by hash in as few
+	// BatchCallContext round trips as the pool's BatchConfig allows, returning a result
+	// and an error per hash so indexer/backfill callers don't have to fan out one
+	// GetTransactionByHash call per hash.
+	GetTransactionsByHashes(ctx context.Context, hashes []common.Hash) ([]*RPCTransaction, []error)
+	// GetTransactionReceipts is the batched counterpart of GetTransactionReceipt.
+	GetTransactionReceipts(ctx context.Context, hashes []common.Hash) ([]*TransactionReceipt, []error)
+	// SubscribePendingTransactions streams the hashes of transactions as they enter the
+	// node's mempool, using the eth_subscribe("newPendingTransactions") extension. It lets
+	// callers build mempool watchers/MEV tooling on a websocket connection instead of
+	// polling PendingTransactions in a loop.
+	SubscribePendingTransactions(ctx context.Context, ch chan<- common.Hash) (Subscription, error)
+	// SubscribeFullPendingTransactions is like SubscribePendingTransactions but streams
+	// full transaction objects instead of bare hashes.
+	SubscribeFullPendingTransactions(ctx context.Context, ch chan<- *RPCTransaction) (Subscription, error)
 	// SendTransaction creates a transaction for the given argument, sign it and submit it to the
 	// transaction pool.
 	SendTransaction(ctx context.Context, args SendTxArgs) (common.Hash, error)
@@ -104,16 +206,72 @@ type PublicTransactionPool interface {
 	PendingTransactions(ctx context.Context) ([]*RPCTransaction, error)
 	// Resend accepts an existing transaction and a new gas price and limit. It will remove
 	// the given transaction from the pool and reinsert it with the new gas price and limit.
-	Resend(ctx context.Context, sendArgs SendTxArgs, gasPrice, gasLimit hexutil.Big) (common.Hash, error)
+	// tipCap and feeCap are optional overrides for the max priority fee and max fee of a
+	// 1559 transaction; they are ignored for legacy transactions.
+	Resend(ctx context.Context, sendArgs SendTxArgs, gasPrice, gasLimit hexutil.Big, tipCap, feeCap *hexutil.Big) (common.Hash, error)
+	// MaxPriorityFeePerGas returns a suggestion for a gas tip cap for dynamic fee transactions.
+	MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error)
+	// FeeHistory returns the base fee, gas used ratio and, optionally, the requested reward
+	// percentiles for a contiguous range of blocks ending at newestBlock.
+	FeeHistory(ctx context.Context, blockCount hexutil.Uint, newestBlock string, rewardPercentiles []float64) (*FeeHistoryResult, error)
 }
 
 type publicTransactionPool struct {
 	client *client.Client
+	signer Signer
+	locker *AddrLocker
+	batch  *batch
 }
 
 func NewPublicTransactionPool(client *client.Client) PublicTransactionPool {
 	return &publicTransactionPool{
 		client: client,
+		locker: NewAddrLocker(),
+		batch:  newBatch(client, DefaultBatchConfig()),
+	}
+}
+
+// NewPublicTransactionPoolWithSigner returns a PublicTransactionPool that signs
+// transactions locally with signer instead of delegating to the node.
+func NewPublicTransactionPoolWithSigner(client *client.Client, signer Signer) PublicTransactionPool {
+	return &publicTransactionPool{
+		client: client,
+		signer: signer,
+		locker: NewAddrLocker(),
+		batch:  newBatch(client, DefaultBatchConfig()),
+	}
+}
+
+// NewPublicTransactionPoolWithLocker returns a PublicTransactionPool that serializes its
+// SendTransaction, SignTransaction and Resend calls through locker, so that multiple
+// PublicTransactionPool instances talking to the same node can share nonce serialization
+// for a given address.
+func NewPublicTransactionPoolWithLocker(client *client.Client, locker *AddrLocker) PublicTransactionPool {
+	return &publicTransactionPool{
+		client: client,
+		locker: locker,
+		batch:  newBatch(client, DefaultBatchConfig()),
+	}
+}
+
+// NewPublicTransactionPoolWithSignerAndLocker combines NewPublicTransactionPoolWithSigner
+// and NewPublicTransactionPoolWithLocker.
+func NewPublicTransactionPoolWithSignerAndLocker(client *client.Client, signer Signer, locker *AddrLocker) PublicTransactionPool {
+	return &publicTransactionPool{
+		client: client,
+		signer: signer,
+		locker: locker,
+		batch:  newBatch(client, DefaultBatchConfig()),
+	}
+}
+
+// NewPublicTransactionPoolWithBatchConfig returns a PublicTransactionPool whose
+// GetTransactionsByHashes and GetTransactionReceipts calls use cfg for batching and retries.
+func NewPublicTransactionPoolWithBatchConfig(client *client.Client, cfg BatchConfig) PublicTransactionPool {
+	return &publicTransactionPool{
+		client: client,
+		locker: NewAddrLocker(),
+		batch:  newBatch(client, cfg),
 	}
 }
 
@@ -208,18 +366,171 @@ func (pub *publicTransactionPool) GetRawTransactionByHash(ctx context.Context, h
 }
 
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
-func (pub *publicTransactionPool) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
-	var r map[string]interface{}
+func (pub *publicTransactionPool) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*TransactionReceipt, error) {
+	var r *TransactionReceipt
 	err := pub.client.CallContext(ctx, &r, "eth_getTransactionReceipt", hash)
 	if err != nil {
-		return r, err
+		return nil, err
 	}
 	return r, nil
 }
 
+// GetTransactionReceiptsByBlock returns the receipts of every transaction in the given
+// block in one round trip, using the eth_getBlockReceipts extension.
+func (pub *publicTransactionPool) GetTransactionReceiptsByBlock(ctx context.Context, blockNrOrHash client.BlockNumberOrHash) ([]*TransactionReceipt, error) {
+	var r []*TransactionReceipt
+	err := pub.client.CallContext(ctx, &r, "eth_getBlockReceipts", blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetTransactionsByHashes looks up multiple transactions by hash in as few
+// BatchCallContext round trips as the pool's BatchConfig allows.
+func (pub *publicTransactionPool) GetTransactionsByHashes(ctx context.Context, hashes []common.Hash) ([]*RPCTransaction, []error) {
+	results := make([]*RPCTransaction, len(hashes))
+	elems := make([]client.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		elems[i] = client.BatchElem{
+			Method: "eth_getTransactionByHash",
+			Args:   []interface{}{hash},
+			Result: &results[i],
+		}
+	}
+	pub.batch.run(ctx, elems)
+
+	errs := make([]error, len(hashes))
+	for i := range elems {
+		errs[i] = elems[i].Error
+	}
+	return results, errs
+}
+
+// GetTransactionReceipts is the batched counterpart of GetTransactionReceipt.
+func (pub *publicTransactionPool) GetTransactionReceipts(ctx context.Context, hashes []common.Hash) ([]*TransactionReceipt, []error) {
+	results := make([]*TransactionReceipt, len(hashes))
+	elems := make([]client.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		elems[i] = client.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{hash},
+			Result: &results[i],
+		}
+	}
+	pub.batch.run(ctx, elems)
+
+	errs := make([]error, len(hashes))
+	for i := range elems {
+		errs[i] = elems[i].Error
+	}
+	return results, errs
+}
+
+// errPartial1559Fee is returned when only one of MaxFeePerGas/MaxPriorityFeePerGas is set.
+// Both are required to build a valid dynamic-fee transaction.
+var errPartial1559Fee = errors.New("rpc: MaxFeePerGas and MaxPriorityFeePerGas must either both be set or both be omitted")
+
+// buildTransaction turns args into a *types.Transaction of the appropriate EIP-2718 type,
+// filling in nonce, chain ID and gas limit from the node when they are not already set.
+func (pub *publicTransactionPool) buildTransaction(ctx context.Context, args SendTxArgs) (*types.Transaction, *big.Int, error) {
+	if (args.MaxFeePerGas == nil) != (args.MaxPriorityFeePerGas == nil) {
+		return nil, nil, errPartial1559Fee
+	}
+
+	chainID := (*big.Int)(args.ChainID)
+	if chainID == nil {
+		var id *hexutil.Big
+		if err := pub.client.CallContext(ctx, &id, "eth_chainId"); err != nil {
+			return nil, nil, err
+		}
+		chainID = (*big.Int)(id)
+	}
+
+	var nonce uint64
+	if args.Nonce != nil {
+		nonce = uint64(*args.Nonce)
+	} else {
+		n, err := pub.GetTransactionCount(ctx, args.From, "pending")
+		if err != nil {
+			return nil, nil, err
+		}
+		nonce = uint64(*n)
+	}
+
+	gas := uint64(args.Gas.ToInt().Int64())
+	if gas == 0 {
+		var g hexutil.Uint64
+		if err := pub.client.CallContext(ctx, &g, "eth_estimateGas", args); err != nil {
+			return nil, nil, err
+		}
+		gas = uint64(g)
+	}
+
+	switch {
+	case args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil:
+		var accessList types.AccessList
+		if args.AccessList != nil {
+			accessList = *args.AccessList
+		}
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      nonce,
+			GasTipCap:  (*big.Int)(args.MaxPriorityFeePerGas),
+			GasFeeCap:  (*big.Int)(args.MaxFeePerGas),
+			Gas:        gas,
+			To:         &args.To,
+			Value:      args.Value.ToInt(),
+			Data:       args.Data,
+			AccessList: accessList,
+		}), chainID, nil
+	case args.AccessList != nil:
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    chainID,
+			Nonce:      nonce,
+			GasPrice:   args.GasPrice.ToInt(),
+			Gas:        gas,
+			To:         &args.To,
+			Value:      args.Value.ToInt(),
+			Data:       args.Data,
+			AccessList: *args.AccessList,
+		}), chainID, nil
+	default:
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: args.GasPrice.ToInt(),
+			Gas:      gas,
+			To:       &args.To,
+			Value:    args.Value.ToInt(),
+			Data:     args.Data,
+		}), chainID, nil
+	}
+}
+
 // SendTransaction creates a transaction for the given argument, sign it and submit it to the
-// transaction pool.
+// transaction pool. If the pool was constructed with a Signer, the transaction is built and
+// signed locally and dispatched via eth_sendRawTransaction; otherwise it is handed to the node
+// as eth_sendTransaction, which requires the from account to be unlocked there.
 func (pub *publicTransactionPool) SendTransaction(ctx context.Context, args SendTxArgs) (common.Hash, error) {
+	pub.locker.LockAddr(args.From)
+	defer pub.locker.UnlockAddr(args.From)
+
+	if pub.signer != nil {
+		tx, chainID, err := pub.buildTransaction(ctx, args)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		signed, err := pub.signer.SignTx(ctx, tx, chainID)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		raw, err := signed.MarshalBinary()
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return pub.SendRawTransaction(ctx, raw)
+	}
+
 	var r common.Hash
 	err := pub.client.CallContext(ctx, &r, "eth_sendTransaction", args)
 	if err != nil {
@@ -245,10 +556,23 @@ func (pub *publicTransactionPool) SendRawTransaction(ctx context.Context, encode
 // Note, the produced signature conforms to the secp256k1 curve R, S and V values,
 // where the V value will be 27 or 28 for legacy reasons.
 //
-// The account associated with addr must be unlocked.
+// If the pool was constructed with a Signer, the signature is produced locally with it.
+// Otherwise the account associated with addr must be unlocked on the node.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_sign
 func (pub *publicTransactionPool) Sign(ctx context.Context, addr common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if pub.signer != nil {
+		sig, err := pub.signer.SignHash(addr, accounts.TextHash(data))
+		if err != nil {
+			return nil, err
+		}
+		// Signers return V as 0 or 1; shift it to the legacy 27/28 form that eth_sign uses.
+		if len(sig) == 65 && sig[64] < 27 {
+			sig[64] += 27
+		}
+		return sig, nil
+	}
+
 	var r hexutil.Bytes
 	err := pub.client.CallContext(ctx, &r, "eth_sign", addr, data)
 	if err != nil {
@@ -259,7 +583,20 @@ func (pub *publicTransactionPool) Sign(ctx context.Context, addr common.Address,
 
 // Resend accepts an existing transaction and a new gas price and limit. It will remove
 // the given transaction from the pool and reinsert it with the new gas price and limit.
-func (pub *publicTransactionPool) Resend(ctx context.Context, sendArgs SendTxArgs, gasPrice, gasLimit hexutil.Big) (common.Hash, error) {
+// tipCap and feeCap are optional overrides for the max priority fee and max fee of a
+// 1559 transaction; they are ignored for legacy transactions.
+func (pub *publicTransactionPool) Resend(ctx context.Context, sendArgs SendTxArgs, gasPrice, gasLimit hexutil.Big, tipCap, feeCap *hexutil.Big) (common.Hash, error) {
+	pub.locker.LockAddr(sendArgs.From)
+	defer pub.locker.UnlockAddr(sendArgs.From)
+
+	if sendArgs.MaxFeePerGas != nil || sendArgs.MaxPriorityFeePerGas != nil {
+		if tipCap != nil {
+			sendArgs.MaxPriorityFeePerGas = tipCap
+		}
+		if feeCap != nil {
+			sendArgs.MaxFeePerGas = feeCap
+		}
+	}
 	var r common.Hash
 	err := pub.client.CallContext(ctx, &r, "eth_resend", sendArgs, gasPrice, gasLimit)
 	if err != nil {
@@ -268,10 +605,52 @@ func (pub *publicTransactionPool) Resend(ctx context.Context, sendArgs SendTxArg
 	return r, nil
 }
 
+// MaxPriorityFeePerGas returns a suggestion for a gas tip cap for dynamic fee transactions.
+func (pub *publicTransactionPool) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	var r *hexutil.Big
+	err := pub.client.CallContext(ctx, &r, "eth_maxPriorityFeePerGas")
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// FeeHistory returns the base fee, gas used ratio and, optionally, the requested reward
+// percentiles for a contiguous range of blocks ending at newestBlock.
+func (pub *publicTransactionPool) FeeHistory(ctx context.Context, blockCount hexutil.Uint, newestBlock string, rewardPercentiles []float64) (*FeeHistoryResult, error) {
+	var r *FeeHistoryResult
+	err := pub.client.CallContext(ctx, &r, "eth_feeHistory", blockCount, newestBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
 // SignTransaction will sign the given transaction with the from account.
-// The node needs to have the private key of the account corresponding with
-// the given from address and it needs to be unlocked.
+//
+// If the pool was constructed with a Signer, the transaction is built and signed locally.
+// Otherwise the node needs to have the private key of the account corresponding with the
+// given from address and it needs to be unlocked.
 func (pub *publicTransactionPool) SignTransaction(ctx context.Context, args SendTxArgs) (*SignTransactionResult, error) {
+	pub.locker.LockAddr(args.From)
+	defer pub.locker.UnlockAddr(args.From)
+
+	if pub.signer != nil {
+		tx, chainID, err := pub.buildTransaction(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		signed, err := pub.signer.SignTx(ctx, tx, chainID)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := signed.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return &SignTransactionResult{Raw: raw, Tx: signed}, nil
+	}
+
 	var r *SignTransactionResult
 	err := pub.client.CallContext(ctx, &r, "eth_signTransaction", args)
 	if err != nil {
@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs transactions and arbitrary message hashes on behalf of an account,
+// without relying on the connected node to hold or unlock the corresponding key.
+// Implementations choose the appropriate EIP-155/EIP-2930/EIP-1559 signer variant
+// for the transaction they are given.
+type Signer interface {
+	// SignTx signs tx for the given chain and returns the signed transaction.
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// SignHash signs hash, which is assumed to already include any required prefix
+	// (e.g. the personal_sign prefix used by eth_sign).
+	SignHash(addr common.Address, hash []byte) ([]byte, error)
+}
+
+// keystoreSigner is a Signer backed by an accounts/keystore account.
+type keystoreSigner struct {
+	ks         *keystore.KeyStore
+	account    accounts.Account
+	passphrase string
+}
+
+// NewKeystoreSigner returns a Signer that signs using the given keystore account,
+// unlocking it with passphrase for each signing operation.
+func NewKeystoreSigner(ks *keystore.KeyStore, account accounts.Account, passphrase string) Signer {
+	return &keystoreSigner{
+		ks:         ks,
+		account:    account,
+		passphrase: passphrase,
+	}
+}
+
+func (s *keystoreSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.ks.SignTxWithPassphrase(s.account, s.passphrase, tx, chainID)
+}
+
+func (s *keystoreSigner) SignHash(addr common.Address, hash []byte) ([]byte, error) {
+	return s.ks.SignHashWithPassphrase(s.account, s.passphrase, hash)
+}
+
+// privateKeySigner is a Signer backed by a raw ECDSA private key held in memory.
+type privateKeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewPrivateKeySigner returns a Signer that signs with the given private key directly.
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) Signer {
+	return &privateKeySigner{key: key}
+}
+
+func (s *privateKeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	return types.SignTx(tx, signer, s.key)
+}
+
+func (s *privateKeySigner) SignHash(addr common.Address, hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.key)
+}
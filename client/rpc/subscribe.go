@@ -0,0 +1,29 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	client "github.com/ethereum/go-ethereum/rpc"
+)
+
+// Subscription represents a subscription established with eth_subscribe. It exposes
+// Unsubscribe() to end the subscription and an Err() channel that fires once, with the
+// termination reason (nil on a clean Unsubscribe), matching go-ethereum's own
+// rpc.ClientSubscription semantics.
+type Subscription = *client.ClientSubscription
+
+// SubscribePendingTransactions streams the hashes of transactions as they enter the node's
+// mempool, built on top of client.Subscribe("eth", ch, "newPendingTransactions"). This lets
+// callers build mempool watchers/MEV tooling on a websocket or IPC connection instead of
+// polling PendingTransactions in a loop.
+func (pub *publicTransactionPool) SubscribePendingTransactions(ctx context.Context, ch chan<- common.Hash) (Subscription, error) {
+	return pub.client.Subscribe(ctx, "eth", ch, "newPendingTransactions")
+}
+
+// SubscribeFullPendingTransactions is like SubscribePendingTransactions but streams full
+// transaction objects instead of bare hashes, using Geth's "newPendingTransactions" filter
+// with the full-object flag set.
+func (pub *publicTransactionPool) SubscribeFullPendingTransactions(ctx context.Context, ch chan<- *RPCTransaction) (Subscription, error) {
+	return pub.client.Subscribe(ctx, "eth", ch, "newPendingTransactions", true)
+}